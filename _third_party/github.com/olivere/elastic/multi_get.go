@@ -5,18 +5,29 @@
 package elastic
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 )
 
 type MultiGetService struct {
-	client     *Client
-	preference string
-	realtime   *bool
-	refresh    *bool
-	items      []*MultiGetItem
+	client       *Client
+	preference   string
+	realtime     *bool
+	refresh      *string
+	routing      string
+	storedFields []string
+	index        string
+	typ          string
+	items        []*MultiGetItem
+	header       http.Header
+	pretty       bool
+	filterPath   []string
+	onBatchError func(batchIndex int, err error) bool
 }
 
 func NewMultiGetService(client *Client) *MultiGetService {
@@ -32,34 +43,139 @@ func (b *MultiGetService) Preference(preference string) *MultiGetService {
 	return b
 }
 
-func (b *MultiGetService) Refresh(refresh bool) *MultiGetService {
+// Refresh sets the refresh policy Elasticsearch applies to the shards
+// involved before performing the mget. Valid values are "true", "false",
+// and "wait_for" (the latter making the request wait for a refresh to
+// happen, so previously indexed documents become visible for read-your-
+// writes flows).
+func (b *MultiGetService) Refresh(refresh string) *MultiGetService {
 	b.refresh = &refresh
 	return b
 }
 
+// RefreshBool is a compatibility shim for callers still passing a plain
+// bool to Refresh. Use Refresh with "true", "false", or "wait_for" instead.
+func (b *MultiGetService) RefreshBool(refresh bool) *MultiGetService {
+	return b.Refresh(fmt.Sprintf("%v", refresh))
+}
+
 func (b *MultiGetService) Realtime(realtime bool) *MultiGetService {
 	b.realtime = &realtime
 	return b
 }
 
+// Routing sets a routing value shared by all documents in this request,
+// unless a document overrides it with its own MultiGetItem.Routing.
+func (b *MultiGetService) Routing(routing string) *MultiGetService {
+	b.routing = routing
+	return b
+}
+
+// StoredFields sets the list of stored fields to retrieve. It is used as
+// the query parameter stored_fields and applied to every item that does
+// not specify its own fields.
+func (b *MultiGetService) StoredFields(storedFields ...string) *MultiGetService {
+	b.storedFields = append(b.storedFields, storedFields...)
+	return b
+}
+
+// Header sets headers on the request, e.g. for tracing or propagating
+// request-scoped metadata to Elasticsearch.
+func (b *MultiGetService) Header(header http.Header) *MultiGetService {
+	b.header = header
+	return b
+}
+
+// Pretty tells Elasticsearch to return indented JSON, mostly useful
+// for debugging.
+func (b *MultiGetService) Pretty(pretty bool) *MultiGetService {
+	b.pretty = pretty
+	return b
+}
+
+// FilterPath allows reducing the response, a mechanism supported by
+// Elasticsearch to reduce the response returned to the minimum
+// necessary, e.g. FilterPath("docs._id", "docs._source").
+func (b *MultiGetService) FilterPath(filterPath ...string) *MultiGetService {
+	b.filterPath = append(b.filterPath, filterPath...)
+	return b
+}
+
 func (b *MultiGetService) Add(items ...*MultiGetItem) *MultiGetService {
 	b.items = append(b.items, items...)
 	return b
 }
 
+// Index sets the default index to use for items that don't specify
+// their own, e.g. the ones added via AddIds.
+func (b *MultiGetService) Index(index string) *MultiGetService {
+	b.index = index
+	return b
+}
+
+// Type sets the default document type to use for items that don't
+// specify their own, e.g. the ones added via AddIds.
+func (b *MultiGetService) Type(typ string) *MultiGetService {
+	b.typ = typ
+	return b
+}
+
+// AddIds is a shortcut for adding multiple MultiGetItem instances that
+// only carry an id, relying on Index and Type for their index/type.
+func (b *MultiGetService) AddIds(ids ...string) *MultiGetService {
+	for _, id := range ids {
+		b.items = append(b.items, NewMultiGetItem().Id(id))
+	}
+	return b
+}
+
 func (b *MultiGetService) Source() interface{} {
 	source := make(map[string]interface{})
+
+	idsOnly := len(b.items) > 0
+	ids := make([]string, 0, len(b.items))
 	items := make([]interface{}, len(b.items))
 	for i, item := range b.items {
+		if item.index == "" {
+			item.index = b.index
+		}
+		if item.typ == "" {
+			item.typ = b.typ
+		}
+		if len(b.storedFields) > 0 && item.fields == nil {
+			item.Fields(b.storedFields...)
+		}
+		if b.client != nil {
+			item.legacyFields = b.client.DeprecatedFieldsCompatibility
+		}
+		if idsOnly && item.isPureID(b.index, b.typ) {
+			ids = append(ids, item.id)
+		} else {
+			idsOnly = false
+		}
 		items[i] = item.Source()
 	}
+
+	if idsOnly {
+		source["ids"] = ids
+		return source
+	}
+
 	source["docs"] = items
 	return source
 }
 
-func (b *MultiGetService) Do() (*MultiGetResult, error) {
+// Do executes the request against Elasticsearch. The given context is
+// used to cancel the request or attach a deadline, and is propagated
+// down to the underlying HTTP call.
+func (b *MultiGetService) Do(ctx context.Context) (*MultiGetResult, error) {
 	// Build url
 	urls := "/_mget"
+	if b.index != "" && b.typ != "" {
+		urls = fmt.Sprintf("/%s/%s/_mget", b.index, b.typ)
+	} else if b.index != "" {
+		urls = fmt.Sprintf("/%s/_mget", b.index)
+	}
 
 	params := make(url.Values)
 	if b.realtime != nil {
@@ -69,7 +185,19 @@ func (b *MultiGetService) Do() (*MultiGetResult, error) {
 		params.Add("preference", b.preference)
 	}
 	if b.refresh != nil {
-		params.Add("refresh", fmt.Sprintf("%v", *b.refresh))
+		params.Add("refresh", *b.refresh)
+	}
+	if b.routing != "" {
+		params.Add("routing", b.routing)
+	}
+	if len(b.storedFields) > 0 {
+		params.Add("stored_fields", strings.Join(b.storedFields, ","))
+	}
+	if b.pretty {
+		params.Add("pretty", "true")
+	}
+	if len(b.filterPath) > 0 {
+		params.Add("filter_path", strings.Join(b.filterPath, ","))
 	}
 	if len(params) > 0 {
 		urls += "?" + params.Encode()
@@ -80,6 +208,13 @@ func (b *MultiGetService) Do() (*MultiGetResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	req = (*Request)((*http.Request)(req).WithContext(ctx))
+
+	for key, values := range b.header {
+		for _, v := range values {
+			(*http.Request)(req).Header.Add(key, v)
+		}
+	}
 
 	// Set body
 	req.SetBodyJson(b.Source())
@@ -100,18 +235,143 @@ func (b *MultiGetService) Do() (*MultiGetResult, error) {
 	return ret, nil
 }
 
+// OnBatchError registers a callback invoked by DoBatched when one of its
+// batches fails. Returning true retries that batch once more; returning
+// false leaves its documents as not-found-with-error and moves on to the
+// remaining batches. The callback is not invoked again if the retry
+// fails as well; that batch's documents are then reported with the error.
+func (b *MultiGetService) OnBatchError(fn func(batchIndex int, err error) bool) *MultiGetService {
+	b.onBatchError = fn
+	return b
+}
+
+// DoBatched splits the items accumulated via Add/AddIds into chunks of
+// at most batchSize and dispatches up to concurrency of them at a time,
+// merging the results back into a single MultiGetResult in the original
+// item order. This avoids building one giant request body when mget-ing
+// tens of thousands of ids, which can overwhelm the coordinating node.
+//
+// Unlike Do, a single failed batch does not abort the whole call: its
+// documents are reported as Found: false with an Error set, so callers
+// can still process the rest of the result. Use OnBatchError to retry or
+// inspect failures as they happen. If every batch fails, DoBatched still
+// returns the (all-errored) result alongside a non-nil error, so a
+// caller that doesn't inspect individual docs can still tell nothing
+// was fetched.
+func (b *MultiGetService) DoBatched(ctx context.Context, batchSize, concurrency int) (*MultiGetResult, error) {
+	if batchSize <= 0 {
+		batchSize = len(b.items)
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if len(b.items) == 0 {
+		return &MultiGetResult{}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items := b.items
+	numBatches := (len(items) + batchSize - 1) / batchSize
+	docs := make([]*GetResult, len(items))
+	var failedBatches int
+	var lastErr error
+
+	type batchJob struct {
+		index int
+		items []*MultiGetItem
+	}
+
+	jobs := make(chan batchJob)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	runBatch := func(j batchJob) (*MultiGetResult, error) {
+		batch := &MultiGetService{
+			client:       b.client,
+			preference:   b.preference,
+			realtime:     b.realtime,
+			refresh:      b.refresh,
+			routing:      b.routing,
+			storedFields: b.storedFields,
+			index:        b.index,
+			typ:          b.typ,
+			header:       b.header,
+			pretty:       b.pretty,
+			filterPath:   b.filterPath,
+			items:        j.items,
+		}
+		return batch.Do(ctx)
+	}
+
+	worker := func() {
+		defer wg.Done()
+		for j := range jobs {
+			res, err := runBatch(j)
+			if err != nil && b.onBatchError != nil && b.onBatchError(j.index, err) {
+				res, err = runBatch(j)
+			}
+
+			start := j.index * batchSize
+			mu.Lock()
+			if err != nil {
+				failedBatches++
+				lastErr = err
+				for i, item := range j.items {
+					docs[start+i] = &GetResult{
+						Index: item.index,
+						Type:  item.typ,
+						Id:    item.id,
+						Found: false,
+						Error: &ErrorDetails{Reason: err.Error()},
+					}
+				}
+			} else {
+				for i, doc := range res.Docs {
+					docs[start+i] = doc
+				}
+			}
+			mu.Unlock()
+		}
+	}
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go worker()
+	}
+
+	for i := 0; i < numBatches; i++ {
+		start := i * batchSize
+		end := start + batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		jobs <- batchJob{index: i, items: items[start:end]}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failedBatches == numBatches {
+		return &MultiGetResult{Docs: docs}, fmt.Errorf("elastic: all %d mget batches failed, last error: %v", numBatches, lastErr)
+	}
+
+	return &MultiGetResult{Docs: docs}, nil
+}
+
 // -- Multi Get Item --
 
 // MultiGetItem is a single document to retrieve via the MultiGetService.
 type MultiGetItem struct {
-	index       string
-	typ         string
-	id          string
-	routing     string
-	fields      []string
-	version     int64  // see org.elasticsearch.common.lucene.uid.Versions
-	versionType string // see org.elasticsearch.index.VersionType
-	fsc         *FetchSourceContext
+	index        string
+	typ          string
+	id           string
+	routing      string
+	fields       []string
+	version      int64  // see org.elasticsearch.common.lucene.uid.Versions
+	versionType  string // see org.elasticsearch.index.VersionType
+	fsc          *FetchSourceContext
+	legacyFields bool // set from Client.DeprecatedFieldsCompatibility by MultiGetService.Source
 }
 
 func NewMultiGetItem() *MultiGetItem {
@@ -169,6 +429,43 @@ func (item *MultiGetItem) FetchSource(fetchSourceContext *FetchSourceContext) *M
 	return item
 }
 
+// SourceIncludes specifies the name of fields to include in the _source
+// returned for this document.
+func (item *MultiGetItem) SourceIncludes(includes ...string) *MultiGetItem {
+	if item.fsc == nil {
+		item.fsc = NewFetchSourceContext(true)
+	}
+	item.fsc.Include(includes...)
+	return item
+}
+
+// SourceExcludes specifies the name of fields to exclude from the
+// _source returned for this document.
+func (item *MultiGetItem) SourceExcludes(excludes ...string) *MultiGetItem {
+	if item.fsc == nil {
+		item.fsc = NewFetchSourceContext(true)
+	}
+	item.fsc.Exclude(excludes...)
+	return item
+}
+
+// isPureID reports whether the item carries nothing but an id that
+// resolves to the given service-level index/type, in which case
+// MultiGetService.Source may serialize it using the compact
+// {"ids": [...]} form instead of a full doc spec. index and typ must be
+// the index/type the item already fell back to, so an item whose own
+// Index/Type overrides the service defaults correctly disqualifies the
+// whole batch from the compact form.
+func (item *MultiGetItem) isPureID(index, typ string) bool {
+	return item.index == index &&
+		item.typ == typ &&
+		item.routing == "" &&
+		item.fields == nil &&
+		item.fsc == nil &&
+		item.version == -3 &&
+		item.versionType == ""
+}
+
 // Source returns the serialized JSON to be sent to Elasticsearch as
 // part of a MultiGet search.
 func (item *MultiGetItem) Source() interface{} {
@@ -187,7 +484,12 @@ func (item *MultiGetItem) Source() interface{} {
 	}
 
 	if item.fields != nil {
-		source["_fields"] = item.fields
+		source["stored_fields"] = item.fields
+		if item.legacyFields {
+			// Deprecated alias for pre-5.x Elasticsearch clusters that
+			// still expect "_fields" instead of "stored_fields".
+			source["_fields"] = item.fields
+		}
 	}
 
 	if item.routing != "" {